@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestNewShakeDRBGWithPersonalizationDomainSeparates asserts that two DRBGs built from the same
+// seed but different (functionName, customization) pairs diverge, since N/S are baked into the
+// cSHAKE state the DRBG is built on.
+func TestNewShakeDRBGWithPersonalizationDomainSeparates(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x77}, RecommendedKMAC256KeyTagSize)
+
+	drbgA, err := NewShakeDRBGWithPersonalization(256, seed, []byte("appA"), []byte("purposeA"))
+	if err != nil {
+		t.Fatalf("NewShakeDRBGWithPersonalization failed: %v", err)
+	}
+	drbgB, err := NewShakeDRBGWithPersonalization(256, seed, []byte("appB"), []byte("purposeB"))
+	if err != nil {
+		t.Fatalf("NewShakeDRBGWithPersonalization failed: %v", err)
+	}
+
+	outA := make([]byte, 32)
+	outB := make([]byte, 32)
+	if err := drbgA.Generate(outA, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if err := drbgB.Generate(outB, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if bytes.Equal(outA, outB) {
+		t.Fatal("DRBGs with different (functionName, customization) produced identical output")
+	}
+}
+
+// TestResetPreservesPersonalization asserts that Reset on a personalized DRBG still produces
+// output consistent with that personalization: resetting with a seed must match a fresh
+// instance constructed with the same N/S/seed.
+func TestResetPreservesPersonalization(t *testing.T) {
+	functionName := []byte("resetTest")
+	customization := []byte("resetPurpose")
+	seedA := bytes.Repeat([]byte{0x11}, RecommendedKMAC256KeyTagSize)
+	seedB := bytes.Repeat([]byte{0x99}, RecommendedKMAC256KeyTagSize)
+
+	drbg, err := NewShakeDRBGWithPersonalization(256, seedA, functionName, customization)
+	if err != nil {
+		t.Fatalf("NewShakeDRBGWithPersonalization failed: %v", err)
+	}
+	// advance past the initial state so Reset is actually exercised, not just the constructor.
+	scratch := make([]byte, 32)
+	if err := drbg.Generate(scratch, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if err := drbg.Reset(seedB); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	afterReset := make([]byte, 32)
+	if err := drbg.Generate(afterReset, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	fresh, err := NewShakeDRBGWithPersonalization(256, seedB, functionName, customization)
+	if err != nil {
+		t.Fatalf("NewShakeDRBGWithPersonalization failed: %v", err)
+	}
+	fromFresh := make([]byte, 32)
+	if err := fresh.Generate(fromFresh, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if !bytes.Equal(afterReset, fromFresh) {
+		t.Fatalf("Reset(seedB) on a personalized DRBG didn't match a fresh instance with the same "+
+			"N/S/seed: %#x vs %#x", afterReset, fromFresh)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/binary"
+)
+
+// nKMAC is the cSHAKE function-name ("N") used by KMAC, per SP 800-185 section 4.
+var nKMAC = []byte("KMAC")
+
+// rightEncode encodes value as a byte string per SP 800-185 section 2.3.1, appending the
+// number of bytes used to encode value as the last byte (the mirror of leftEncode, which
+// prepends it).
+func rightEncode(value uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], value)
+	i := 0
+	for i < 7 && b[i] == 0 {
+		i++
+	}
+	n := byte(len(b) - i)
+	return append(b[i:], n)
+}
+
+// encodeString encodes s as left_encode(len(s)*8) || s, per SP 800-185 section 2.3.2.
+func encodeString(s []byte) []byte {
+	return append(leftEncode(uint64(len(s)*8)), s...)
+}
+
+// KMACPRNG is a KMAC128/KMAC256 based DRBG built on cSHAKE, per NIST SP 800-185.
+// Unlike ShakePRNG, which manually XORs seed material into a bare SHAKE128/256 instance,
+// KMACPRNG keys the underlying cSHAKE state with bytepad(encode_string(key), rate) as its
+// first absorbed block, giving a real, standards-conformant keyed DRBG.
+type KMACPRNG struct {
+	// set the following at object creation
+	c       ShakeHash // cSHAKE instance with N = "KMAC"
+	rate    int       // Keccak rate
+	seedLen int       // recommended seed length
+	// the following are operational variables (no initialization needed at creation)
+	counter int // counter for reseed
+}
+
+// Generate generates a random bit stream to fill []byte out.
+// Optional (but recommended) additionalIn is used to help the generation and increase its
+// resistance to hackers.
+// An error wrapping ErrDRBGReseed will be returned if the DRBG needs to be reseeded (use
+// errors.Is to test). It's destroyed on error.
+func (s *KMACPRNG) Generate(out, additionalIn []byte) error {
+	if s.NeedReseed() {
+		return WrapTraceableErrorf(ErrDRBGReseed, "exceeding reseed limit: reseed before generating PRNG")
+	}
+	lo, li := len(out), len(additionalIn)
+	if lo > MaxInputLength {
+		return WrapTraceableErrorf(nil, "output length (%d) too long: needs at most %d bytes", lo, MaxInputLength)
+	} else if lo == 0 {
+		return WrapTraceableErrorf(nil, "empty output specified")
+	}
+	if li > MaxInputLength {
+		return WrapTraceableErrorf(nil, "additional input length (%d) too long: needs at most %d bytes",
+			li, MaxInputLength)
+	}
+	if li > 0 {
+		// absorb right_encode(len(additionalIn)*8) || additionalIn for domain separation between calls
+		if _, err := s.c.Write(append(rightEncode(uint64(li*8)), additionalIn...)); err != nil {
+			s.Destroy()
+			return WrapTraceableErrorf(err, "failed to write additional input into DRBG hash")
+		}
+	}
+	// clone the hash before appending right_encode(L) and reading, since cSHAKE won't allow us to
+	// write after read; dup carries the DRBG state forward for the next Generate/Reseed call.
+	dup := s.c.Clone()
+	lenEnc := rightEncode(uint64(lo * 8))
+	if _, err := s.c.Write(lenEnc); err != nil {
+		s.Destroy()
+		return WrapTraceableErrorf(err, "failed to write output length encoding into DRBG hash")
+	}
+	if _, err := s.c.Read(out); err != nil {
+		s.Destroy()
+		return WrapTraceableErrorf(err, "failed to generate PRNG from DRBG hash")
+	}
+	if _, err := dup.Write(lenEnc); err != nil {
+		s.Destroy()
+		dup.Reset()
+		return WrapTraceableErrorf(err, "failed to carry output length encoding forward into DRBG hash")
+	}
+	s.c = dup
+	s.counter++
+	return nil
+}
+
+// Reseed reseeds the DRBG with seed.
+// It's destroyed on error.
+func (s *KMACPRNG) Reseed(seed []byte) error {
+	minLen := s.seedLen / 2
+	l := len(seed)
+	if l < minLen {
+		return WrapTraceableErrorf(nil, "seed length (%d) too short: needs at least %d bytes", l, minLen)
+	}
+	if l > MaxInputLength {
+		return WrapTraceableErrorf(nil, "seed length (%d) too long: needs at most %d bytes", l, MaxInputLength)
+	}
+	if _, err := s.c.Write(append(rightEncode(uint64(l*8)), seed...)); err != nil {
+		s.Destroy()
+		return WrapTraceableErrorf(err, "failed to write seed into DRBG hash")
+	}
+	s.counter = 0
+	return nil
+}
+
+// NeedReseed indicates if the DRBG needs to be reseeded.
+func (s KMACPRNG) NeedReseed() bool {
+	return s.counter > ReseedInterval
+}
+
+// Destroy clears DRBG's internal states. It's not usable afterwards.
+func (s *KMACPRNG) Destroy() {
+	s.c.Reset()
+	s.c = nil
+	s.rate, s.seedLen, s.counter = 0, 0, 0
+}
+
+// NewKMACDRBG returns a new KMAC128/KMAC256 DRBG keyed with key, domain-separated by
+// personalization, and seeded with seed.
+// Per the NIST std, seed is the concatenated bytes of entropy_input, nonce, and
+// personalized_string.
+func NewKMACDRBG(bits int, key, personalization, seed []byte) (*KMACPRNG, error) {
+	if err := SelfTest(); err != nil {
+		return nil, WrapTraceableErrorf(err, "DRBG self-test failed")
+	}
+	var drbg KMACPRNG
+	switch bits {
+	case 128:
+		drbg.seedLen = RecommendedKMAC128KeyTagSize
+		drbg.rate = rate128
+	case 256:
+		drbg.seedLen = RecommendedKMAC256KeyTagSize
+		drbg.rate = rate256
+	default:
+		return nil, WrapTraceableErrorf(nil, "unsupported size (%d) for KMAC DRBG", bits)
+	}
+	drbg.c = newCShake(nKMAC, personalization, drbg.rate, dsbyteCShake)
+	// key the cSHAKE state with bytepad(encode_string(key), rate) as the first absorbed block,
+	// per the KMAC construction in SP 800-185 section 4.
+	if _, err := drbg.c.Write(bytepad(encodeString(key), drbg.rate)); err != nil {
+		return nil, WrapTraceableErrorf(err, "failed to key the KMAC%d DRBG", bits)
+	}
+	if err := drbg.Reseed(seed); err != nil {
+		return nil, WrapTraceableErrorf(err, "failed to initialize the KMAC%d DRBG", bits)
+	}
+	return &drbg, nil
+}
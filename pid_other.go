@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+// currentPID is a no-op on platforms without a meaningful fork model: it always returns the
+// same value, so DRBGReader's fork-detection check never trips. It's a var, not a func, so
+// tests can substitute it to simulate a fork.
+var currentPID = func() int { return 0 }
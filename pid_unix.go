@@ -0,0 +1,10 @@
+//go:build unix
+
+package main
+
+import "os"
+
+// currentPID returns the current process ID, used to detect forks so the DRBG can be reseeded
+// before a forked child risks reproducing the parent's output stream. It's a var, not a func,
+// so tests can substitute it to simulate a fork without actually forking the process.
+var currentPID = os.Getpid
@@ -4,6 +4,7 @@ import (
   "errors"
   "fmt"
   "math/bits"
+  "sync"
 )
 
 const (
@@ -40,13 +41,23 @@ type ShakePRNG struct {
 	c       ShakeHash // use SHAKExxx hash only!
 	rate    int            // Keccak rate
 	seedLen int            // recommended seed length
+	// PredictionResistance, when true, makes Generate pull fresh entropy from EntropySource and
+	// reseed before producing output on every call, per NIST SP 800-90A section 9.3.2. It
+	// requires EntropySource to be set.
+	PredictionResistance bool
+	// EntropySource, when set, is used to automatically reseed the DRBG: whenever the reseed
+	// interval is exceeded, and on every call when PredictionResistance is true.
+	EntropySource func(nBytes int) ([]byte, error)
 	// the following are operational variables (no initialization needed at creation)
 	counter int // counter for reseed
   nw int
+	// mu serializes every method below, so a single ShakePRNG can be shared across goroutines
+	// (e.g. wrapped by more than one DRBGReader, or used directly alongside a DRBGReader).
+	mu sync.Mutex
 }
 
 // gen10x01Pad generates the 10*01 padding for n bytes long
-func (s ShakePRNG) gen10x01Pad(n int) []byte {
+func (s *ShakePRNG) gen10x01Pad(n int) []byte {
 	const dsbyteShake = 0x1f // SHAKE domain-separator bits (0x0f) plus the first one bit for the 10*01 padding
 	if n <= 0 {
 		return nil
@@ -66,7 +77,6 @@ func (s *ShakePRNG) writeWithPad(c ShakeHash, p []byte, start byte) error {
 		return nil
 	}
   offset := int(start)
-  fmt.Printf("writeWithPad offset: %#x\n", offset)
 	// use s.nw to create a pre-pad so that we won't always XOR the same starting place in SHAKE states
 	buf := make([]byte, offset+len(p))
 	copy(buf[offset:], p)
@@ -84,7 +94,7 @@ func (s *ShakePRNG) writeWithPad(c ShakeHash, p []byte, start byte) error {
 	return nil
 }
 
-func (s ShakePRNG) getStartFrom(buf []byte) byte {
+func (s *ShakePRNG) getStartFrom(buf []byte) byte {
 	// We use the XOR of all bytes in buf (with a proper modulo) to set the starting index of states to be cleared.
 	// In this way, we don't always start from the same place which is harder for the hacker to track.
 	sum := byte(0)
@@ -145,9 +155,30 @@ func (s *ShakePRNG) createMask(b []byte) {
 // Generate generates a random bit stearm to fill []byte out.
 // Optional (but recommended) additionalIn is used to help the generation and increase its resistance to hackers
 // An error wrapping ErrDRBGReseed will be returned if the DRBG needs to be reseeded (use errors.Is to test)
-// It's destroyed on error.
+// It's destroyed on error. Safe for concurrent use.
 func (s *ShakePRNG) Generate(out, additionalIn []byte) error {
-	if s.NeedReseed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generateLocked(out, additionalIn)
+}
+
+// generateLocked is Generate's body. s.mu must already be held.
+func (s *ShakePRNG) generateLocked(out, additionalIn []byte) error {
+	if s.PredictionResistance {
+		if s.EntropySource == nil {
+			return WrapTraceableErrorf(nil, "prediction resistance enabled but no EntropySource configured")
+		}
+		entropy, err := s.EntropySource(s.seedLen)
+		if err != nil {
+			return WrapTraceableErrorf(err, "failed to pull entropy from EntropySource")
+		}
+		// reseed with entropy_input || additional_input, then clear additionalIn, per
+		// SP 800-90A section 9.3.2's prediction-resistance generate function.
+		if err := s.reseedLocked(append(entropy, additionalIn...)); err != nil {
+			return WrapTraceableErrorf(err, "failed to reseed DRBG for prediction resistance")
+		}
+		additionalIn = nil
+	} else if s.needReseedLocked() {
 		return WrapTraceableErrorf(ErrDRBGReseed, "exceeding reseed limit: reseed before generating PRNG")
 	}
 	lo, li := len(out), len(additionalIn)
@@ -162,27 +193,24 @@ func (s *ShakePRNG) Generate(out, additionalIn []byte) error {
 		return WrapTraceableErrorf(nil, "additional input length (%d) too long: needs at most %d bytes",
 			li, MaxInputLength)
 	}
-  fmt.Printf("* Generate additionalIn write...\n")
 	if err := s.writeWithPad(s.c, additionalIn, s.getStartFrom(additionalIn)); err != nil {
-		s.Destroy()
+		s.destroyLocked()
 		return WrapTraceableErrorf(err, "failed to write additional input into DRBG hash")
 	}
 	// create a cloned hash before Read() since SHAKE hash won't allow us to write after read.
 	dup := s.c.Clone()
 	// make sure buf's last chunk is always s.rate-1 bytes long
 	// read s.rate-1 bytes so that it won't trigger Keccak permute on states
-  fmt.Printf("* Generate Read...\n")
 	buf := make([]byte, ((lo/s.rate)+1)*s.rate - 1)
 	if _, err := s.c.Read(buf); err != nil {
-		s.Destroy()
+		s.destroyLocked()
 		return WrapTraceableErrorf(err, "failed to generate PRNG from DRBG hash")
 	}
 	copy(out, buf)
 	// simulate read on dup
 	// First, from write (absorb) to read (squeeze), SHAKE hash generates a 10*01 pad
-  fmt.Printf("* Generate dup write 10*01 pad...\n")
 	if _, err := dup.Write(s.gen10x01Pad(s.rate)); err != nil {
-		s.Destroy()
+		s.destroyLocked()
 		dup.Reset()
 		return WrapTraceableErrorf(err, "failed to write 10*01 pad into DRBG hash")
 	}
@@ -191,9 +219,8 @@ func (s *ShakePRNG) Generate(out, additionalIn []byte) error {
 	nq := nr / s.rate
 	cBuf := make([]byte, s.rate) // 0 pads
 	for ; nq > 0; nq-- {
-    fmt.Printf("* generate dup simulate-read write...\n")
 		if _, err := dup.Write(cBuf); err != nil {
-			s.Destroy()
+			s.destroyLocked()
 			dup.Reset()
 			return WrapTraceableErrorf(err, "failed to write 0 pad into DRBG hash")
 		}
@@ -203,13 +230,9 @@ func (s *ShakePRNG) Generate(out, additionalIn []byte) error {
 	start := int(s.getStartFrom(buf)) % (s.rate - nClear - 1) // within the last chunk of s.rate-1 bytes
 	offset := nq*s.rate + start                     // within buf
 	b := buf[offset : offset+nClear]
-  fmt.Printf("** start: %#x (%#x), b: %#x\n", 
-      start, offset, b)
 	s.createMask(b)
-  fmt.Printf("** mask: %#x\n", b)
-  fmt.Printf("* Generate state clear write...\n")
 	if err := s.writeWithPad(dup, b, byte(start)); err != nil {
-		s.Destroy()
+		s.destroyLocked()
 		return WrapTraceableErrorf(err, "failed to write masks into DRBG hash")
 	}
 	s.c.Reset()
@@ -219,8 +242,15 @@ func (s *ShakePRNG) Generate(out, additionalIn []byte) error {
 }
 
 // Reseed reseeds the DRBG with seed
-// It's destroyed on error.
+// It's destroyed on error. Safe for concurrent use.
 func (s *ShakePRNG) Reseed(seed []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reseedLocked(seed)
+}
+
+// reseedLocked is Reseed's body. s.mu must already be held.
+func (s *ShakePRNG) reseedLocked(seed []byte) error {
 	minLen := s.seedLen / 2
 	l := len(seed)
 	if l < minLen {
@@ -229,32 +259,83 @@ func (s *ShakePRNG) Reseed(seed []byte) error {
 	if l > MaxInputLength {
 		return WrapTraceableErrorf(nil, "seed length (%d) too long: needs at most %d bytes", l, MaxInputLength)
 	}
-  fmt.Printf("* Seed write...\n")
 	if err := s.writeWithPad(s.c, seed, s.getStartFrom(seed)); err != nil {
-		s.Destroy()
+		s.destroyLocked()
 		return WrapTraceableErrorf(err, "failed to write seed into DRBG hash")
 	}
 	s.counter = 0
 	return nil
 }
 
-// NeedReseed indicates if the DRBG needs to be reseeded
-func (s ShakePRNG) NeedReseed() bool {
+// NeedReseed indicates if the DRBG needs to be reseeded. If an EntropySource is configured, it
+// reseeds from it automatically instead and only reports true if that reseed fails. Safe for
+// concurrent use.
+func (s *ShakePRNG) NeedReseed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.needReseedLocked()
+}
+
+// needReseedLocked is NeedReseed's body. s.mu must already be held.
+func (s *ShakePRNG) needReseedLocked() bool {
 	// In theory, this should be r*2^(r/2) bits where r is the rate (in bits). However, 2^48 (defined by NIST for DRBG)
 	// is big enough in practice.
-	return s.counter > ReseedInterval
+	if s.counter <= ReseedInterval {
+		return false
+	}
+	if s.EntropySource == nil {
+		return true
+	}
+	return s.reseedFromSourceLocked() != nil
+}
+
+// ReseedFromSource reseeds the DRBG with fresh entropy pulled from EntropySource. Safe for
+// concurrent use.
+func (s *ShakePRNG) ReseedFromSource() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reseedFromSourceLocked()
+}
+
+// reseedFromSourceLocked is ReseedFromSource's body. s.mu must already be held.
+func (s *ShakePRNG) reseedFromSourceLocked() error {
+	if s.EntropySource == nil {
+		return WrapTraceableErrorf(nil, "no EntropySource configured for DRBG")
+	}
+	entropy, err := s.EntropySource(s.seedLen)
+	if err != nil {
+		return WrapTraceableErrorf(err, "failed to pull entropy from EntropySource")
+	}
+	return s.reseedLocked(entropy)
+}
+
+// SeedLen returns the recommended seed length in bytes for this DRBG instance. Safe for
+// concurrent use.
+func (s *ShakePRNG) SeedLen() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seedLen
 }
 
 // Reset resets the states of the DRBG and starts it with seed
-// It's destroyed on error.
+// It's destroyed on error. Safe for concurrent use.
 func (s *ShakePRNG) Reset(seed []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.c.Reset()
   s.nw = 0
-	return s.Reseed(seed)
+	return s.reseedLocked(seed)
 }
 
-// Destroy clears DRBG's internal states. It's not usable afterwards.
+// Destroy clears DRBG's internal states. It's not usable afterwards. Safe for concurrent use.
 func (s *ShakePRNG) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.destroyLocked()
+}
+
+// destroyLocked is Destroy's body. s.mu must already be held.
+func (s *ShakePRNG) destroyLocked() {
 	s.c.Reset()
 	s.c = nil
 	s.rate, s.seedLen, s.counter, s.nw = 0, 0, 0, 0
@@ -263,19 +344,38 @@ func (s *ShakePRNG) Destroy() {
 // NewShakeDRBG returns a new KMAC hash DRBG with seed
 // Per the NIST std, seed is the concatenated bytes of entropy_input, nonce, and personalized_string.
 func NewShakeDRBG(bits int, seed []byte) (*ShakePRNG, error) {
+	return NewShakeDRBGWithPersonalization(bits, seed, nil, nil)
+}
+
+// NewShakeDRBGWithPersonalization returns a new cSHAKE hash DRBG with seed, domain-separated by
+// the cSHAKE function-name functionName (N) and customization (S) strings.
+// If both functionName and customization are empty, the DRBG falls back to plain SHAKE128/256,
+// identical to NewShakeDRBG. Per the NIST std, seed is the concatenated bytes of entropy_input,
+// nonce, and personalized_string. Reset and Reseed preserve the personalization across resets,
+// since it's baked into the underlying cSHAKE state rather than into the DRBG itself.
+func NewShakeDRBGWithPersonalization(bits int, seed, functionName, customization []byte) (*ShakePRNG, error) {
+	if err := SelfTest(); err != nil {
+		return nil, WrapTraceableErrorf(err, "DRBG self-test failed")
+	}
 	var drbg ShakePRNG
+	personalized := len(functionName) > 0 || len(customization) > 0
 	switch bits {
 	case 128:
 		drbg.seedLen = RecommendedKMAC128KeyTagSize
-		drbg.c = NewShake128()
 		drbg.rate = rate128
 	case 256:
 		drbg.seedLen = RecommendedKMAC256KeyTagSize
-		drbg.c = NewShake256()
 		drbg.rate = rate256
 	default:
 		return nil, WrapTraceableErrorf(nil, "unsupported size (%d) for SHAKE DRBG", bits)
 	}
+	if personalized {
+		drbg.c = newCShake(functionName, customization, drbg.rate, dsbyteCShake)
+	} else if bits == 128 {
+		drbg.c = NewShake128()
+	} else {
+		drbg.c = NewShake256()
+	}
 	// initialize it
 	if err := drbg.Reset(seed); err != nil {
 		return nil, WrapTraceableErrorf(err, "failed to initialize the SHAKE%d DRBG", bits)
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+// counterEntropySource returns deterministic, ever-changing entropy so tests can tell reseeds
+// apart without needing a real entropy source.
+func counterEntropySource() (func(nBytes int) ([]byte, error), *int) {
+	calls := 0
+	src := func(nBytes int) ([]byte, error) {
+		calls++
+		out := make([]byte, nBytes)
+		for i := range out {
+			out[i] = byte(calls)
+		}
+		return out, nil
+	}
+	return src, &calls
+}
+
+// TestGeneratePredictionResistancePullsFreshEntropyEveryCall asserts that with
+// PredictionResistance enabled, Generate reseeds from EntropySource on every call, so two calls
+// with identical additionalIn still diverge.
+func TestGeneratePredictionResistancePullsFreshEntropyEveryCall(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x55}, RecommendedKMAC256KeyTagSize)
+	drbg, err := NewShakeDRBG(256, seed)
+	if err != nil {
+		t.Fatalf("NewShakeDRBG failed: %v", err)
+	}
+	src, calls := counterEntropySource()
+	drbg.EntropySource = src
+	drbg.PredictionResistance = true
+
+	additionalIn := []byte("same additional input every time")
+	out1 := make([]byte, 32)
+	out2 := make([]byte, 32)
+	if err := drbg.Generate(out1, additionalIn); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if err := drbg.Generate(out2, additionalIn); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if bytes.Equal(out1, out2) {
+		t.Fatal("Generate with PredictionResistance produced identical output across calls")
+	}
+	if *calls != 2 {
+		t.Fatalf("expected EntropySource to be pulled once per Generate call, got %d calls for 2 Generate calls", *calls)
+	}
+}
+
+// TestReseedFromSource asserts that ReseedFromSource pulls entropy from EntropySource and
+// reseeds the DRBG with it.
+func TestReseedFromSource(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x55}, RecommendedKMAC256KeyTagSize)
+	drbg, err := NewShakeDRBG(256, seed)
+	if err != nil {
+		t.Fatalf("NewShakeDRBG failed: %v", err)
+	}
+	before := make([]byte, 32)
+	if err := drbg.Generate(before, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	src, calls := counterEntropySource()
+	drbg.EntropySource = src
+	if err := drbg.ReseedFromSource(); err != nil {
+		t.Fatalf("ReseedFromSource failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Fatalf("expected EntropySource to be pulled exactly once, got %d", *calls)
+	}
+
+	after := make([]byte, 32)
+	if err := drbg.Generate(after, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if bytes.Equal(before, after) {
+		t.Fatal("Generate output didn't change after ReseedFromSource")
+	}
+}
+
+// TestNeedReseedAutoReseedsFromSource asserts that once the reseed interval is exceeded,
+// NeedReseed reseeds automatically from EntropySource instead of reporting true, as long as one
+// is configured.
+func TestNeedReseedAutoReseedsFromSource(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x55}, RecommendedKMAC256KeyTagSize)
+	drbg, err := NewShakeDRBG(256, seed)
+	if err != nil {
+		t.Fatalf("NewShakeDRBG failed: %v", err)
+	}
+	src, calls := counterEntropySource()
+	drbg.EntropySource = src
+	drbg.counter = ReseedInterval + 1 // simulate having exceeded the reseed interval
+
+	if drbg.NeedReseed() {
+		t.Fatal("NeedReseed reported true despite an EntropySource being configured")
+	}
+	if *calls != 1 {
+		t.Fatalf("expected NeedReseed to pull entropy exactly once, got %d", *calls)
+	}
+	if drbg.counter != 0 {
+		t.Fatalf("expected counter to be reset by the auto-reseed, got %d", drbg.counter)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+)
+
+// DRBGReader wraps a *ShakePRNG in the io.Reader and math/rand/v2.Source interfaces, chunking
+// reads into MaxBytesPerRequest-sized Generate calls and transparently reseeding from entropy
+// whenever the DRBG reports ErrDRBGReseed. It is safe for concurrent use: its own mu serializes
+// entropy reads and fork detection, while the wrapped ShakePRNG's internal mutex serializes the
+// underlying hash state, so multiple DRBGReaders over the same ShakePRNG (or a DRBGReader used
+// alongside direct Generate/Reseed calls) don't race.
+type DRBGReader struct {
+	mu      sync.Mutex
+	prng    *ShakePRNG
+	entropy io.Reader
+	pid     int // pid recorded at creation/last reseed, used for fork detection
+}
+
+// Reader returns a DRBGReader wrapping s, reseeding from entropy whenever s needs it.
+func (s *ShakePRNG) Reader(entropy io.Reader) *DRBGReader {
+	return &DRBGReader{prng: s, entropy: entropy, pid: currentPID()}
+}
+
+// NewDRBGReader returns a DRBGReader backed by a freshly-created ShakePRNG, seeded with
+// entropy_input || nonce pulled from entropy, so it can be used as a drop-in replacement for
+// crypto/rand.Reader.
+func NewDRBGReader(bits int, entropy io.Reader, personalization []byte) (*DRBGReader, error) {
+	var seedLen int
+	switch bits {
+	case 128:
+		seedLen = RecommendedKMAC128KeyTagSize
+	case 256:
+		seedLen = RecommendedKMAC256KeyTagSize
+	default:
+		return nil, WrapTraceableErrorf(nil, "unsupported size (%d) for SHAKE DRBG", bits)
+	}
+	// the nonce only needs half the security strength of the seed, per SP 800-90A section 8.6.7.
+	seed := make([]byte, seedLen+seedLen/2)
+	if _, err := io.ReadFull(entropy, seed); err != nil {
+		return nil, WrapTraceableErrorf(err, "failed to read entropy for DRBG seed")
+	}
+	prng, err := NewShakeDRBGWithPersonalization(bits, seed, nil, personalization)
+	if err != nil {
+		return nil, WrapTraceableErrorf(err, "failed to create SHAKE%d DRBG", bits)
+	}
+	return prng.Reader(entropy), nil
+}
+
+// Read fills p with output from the underlying DRBG, chunking into MaxBytesPerRequest-sized
+// Generate calls and reseeding from entropy whenever the DRBG needs it. It never returns fewer
+// bytes than len(p) unless an error occurs.
+func (r *DRBGReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkForkLocked(); err != nil {
+		return 0, err
+	}
+	n := 0
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > MaxBytesPerRequest {
+			chunk = chunk[:MaxBytesPerRequest]
+		}
+		if err := r.prng.Generate(chunk, nil); err != nil {
+			if errors.Is(err, ErrDRBGReseed) {
+				if rerr := r.reseedLocked(); rerr != nil {
+					return n, rerr
+				}
+				continue // retry this chunk now that the DRBG has been reseeded
+			}
+			return n, err
+		}
+		n += len(chunk)
+	}
+	return n, nil
+}
+
+// Uint64 implements math/rand/v2.Source by pulling 8 bytes from the DRBG.
+func (r *DRBGReader) Uint64() uint64 {
+	var b [8]byte
+	if _, err := r.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// reseedLocked reseeds the wrapped DRBG from r.entropy. r.mu must already be held.
+func (r *DRBGReader) reseedLocked() error {
+	seed := make([]byte, r.prng.SeedLen())
+	if _, err := io.ReadFull(r.entropy, seed); err != nil {
+		return WrapTraceableErrorf(err, "failed to read entropy for DRBG reseed")
+	}
+	return r.prng.Reseed(seed)
+}
+
+// checkForkLocked forces a reseed if the process has forked since the DRBG was last (re)seeded,
+// since a forked child otherwise shares (and could diverge identically from) the parent's state.
+// r.mu must already be held.
+func (r *DRBGReader) checkForkLocked() error {
+	if pid := currentPID(); pid != r.pid {
+		if err := r.reseedLocked(); err != nil {
+			return err
+		}
+		r.pid = pid
+	}
+	return nil
+}
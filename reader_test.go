@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// TestDRBGReaderConcurrentRead exercises multiple DRBGReaders wrapping the same *ShakePRNG from
+// concurrent goroutines, which only holds together because ShakePRNG itself (not just
+// DRBGReader.mu) serializes access to the shared hash state.
+func TestDRBGReaderConcurrentRead(t *testing.T) {
+	prng, err := NewShakeDRBG(256, bytes.Repeat([]byte{0x42}, 96))
+	if err != nil {
+		t.Fatalf("NewShakeDRBG failed: %v", err)
+	}
+	entropy := bytes.NewReader(bytes.Repeat([]byte{0x24}, 1<<20))
+
+	const readers = 8
+	const itersPerReader = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, readers)
+	for i := 0; i < readers; i++ {
+		r := prng.Reader(entropy)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 64)
+			for j := 0; j < itersPerReader; j++ {
+				if _, err := r.Read(buf); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent Read failed: %v", err)
+	}
+}
+
+// TestDRBGReaderForkDetectionReseeds verifies that checkForkLocked notices a simulated pid
+// change and forces a reseed by pulling extra seed material from entropy before the next Read.
+func TestDRBGReaderForkDetectionReseeds(t *testing.T) {
+	origPID := currentPID
+	defer func() { currentPID = origPID }()
+
+	currentPID = func() int { return 100 }
+	entropy := bytes.NewReader(bytes.Repeat([]byte{0x24}, 1<<20))
+	prng, err := NewShakeDRBG(256, bytes.Repeat([]byte{0x42}, 96))
+	if err != nil {
+		t.Fatalf("NewShakeDRBG failed: %v", err)
+	}
+	r := prng.Reader(entropy)
+
+	buf := make([]byte, 64)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("initial Read failed: %v", err)
+	}
+	before := entropy.Len()
+
+	currentPID = func() int { return 200 }
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("post-fork Read failed: %v", err)
+	}
+	after := entropy.Len()
+
+	if after >= before {
+		t.Fatalf("expected checkForkLocked to consume entropy for a reseed after a simulated fork, "+
+			"before=%d after=%d", before, after)
+	}
+}
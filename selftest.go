@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+)
+
+// ErrSelfTestFailed indicates that the FIPS-202/SP 800-185 known-answer self-check did not
+// reproduce the published NIST sample vectors, and the DRBG refuses to be constructed.
+var ErrSelfTestFailed = errors.New("DRBG self-test failed")
+
+var selfTestOnce sync.Once
+var selfTestResult error
+
+// katVector is one FIPS-202/SP 800-185 known-answer test case.
+type katVector struct {
+	name string
+	run  func() ([]byte, error)
+	want []byte
+}
+
+// selfTestKATs holds the known-answer test cases run by selfTest.
+var selfTestKATs = []katVector{
+	{
+		name: `SHAKE128("")`,
+		run: func() ([]byte, error) {
+			out := make([]byte, 32)
+			_, err := NewShake128().Read(out)
+			return out, err
+		},
+		want: []byte{
+			0x7f, 0x9c, 0x2b, 0xa4, 0xe8, 0x8f, 0x82, 0x7d, 0x61, 0x60, 0x45, 0x50, 0x76, 0x05, 0x85, 0x3e,
+			0xd7, 0x3b, 0x80, 0x93, 0xf6, 0xef, 0xbc, 0x88, 0xeb, 0x1a, 0x6e, 0xac, 0xfa, 0x66, 0xef, 0x26,
+		},
+	},
+	{
+		name: `SHAKE256("")`,
+		run: func() ([]byte, error) {
+			out := make([]byte, 32)
+			_, err := NewShake256().Read(out)
+			return out, err
+		},
+		want: []byte{
+			0x46, 0xb9, 0xdd, 0x2b, 0x0b, 0xa8, 0x8d, 0x13, 0x23, 0x3b, 0x3f, 0xeb, 0x74, 0x3e, 0xeb, 0x24,
+			0x3f, 0xcd, 0x52, 0xea, 0x62, 0xb8, 0x1b, 0x82, 0xb5, 0x0c, 0x27, 0x64, 0x6e, 0xd5, 0x76, 0x2f,
+		},
+	},
+	{
+		name: `cSHAKE128(00010203, N="", S="Email Signature")`,
+		run: func() ([]byte, error) {
+			h := newCShake(nil, []byte("Email Signature"), rate128, dsbyteCShake)
+			if _, err := h.Write([]byte{0x00, 0x01, 0x02, 0x03}); err != nil {
+				return nil, err
+			}
+			out := make([]byte, 32)
+			_, err := h.Read(out)
+			return out, err
+		},
+		want: []byte{
+			0xc1, 0xc3, 0x69, 0x25, 0xb6, 0x40, 0x9a, 0x04, 0xf1, 0xb5, 0x04, 0xfc, 0xbc, 0xa9, 0xd8, 0x2b,
+			0x40, 0x17, 0x27, 0x7c, 0xb5, 0xed, 0x2b, 0x20, 0x65, 0xfc, 0x1d, 0x38, 0x14, 0xd5, 0xaa, 0xf5,
+		},
+	},
+	{
+		name: `KMAC128(K, 00010203, L=256, S="")`,
+		run: func() ([]byte, error) {
+			key := make([]byte, 32)
+			for i := range key {
+				key[i] = 0x40 + byte(i)
+			}
+			return kmacTagKAT(key, []byte{0x00, 0x01, 0x02, 0x03}, nil, rate128, 256)
+		},
+		want: []byte{
+			0xe5, 0x78, 0x0b, 0x0d, 0x3e, 0xa6, 0xf7, 0xd3, 0xa4, 0x29, 0xc5, 0x70, 0x6a, 0xa4, 0x3a, 0x00,
+			0xfa, 0xdb, 0xd7, 0xd4, 0x96, 0x28, 0x83, 0x9e, 0x31, 0x87, 0x24, 0x3f, 0x45, 0x6e, 0xe1, 0x4e,
+		},
+	},
+	{
+		name: `cSHAKE256(00010203, N="", S="Email Signature")`,
+		run: func() ([]byte, error) {
+			h := newCShake(nil, []byte("Email Signature"), rate256, dsbyteCShake)
+			if _, err := h.Write([]byte{0x00, 0x01, 0x02, 0x03}); err != nil {
+				return nil, err
+			}
+			out := make([]byte, 64)
+			_, err := h.Read(out)
+			return out, err
+		},
+		want: []byte{
+			0xd0, 0x08, 0x82, 0x8e, 0x2b, 0x80, 0xac, 0x9d, 0x22, 0x18, 0xff, 0xee, 0x1d, 0x07, 0x0c, 0x48,
+			0xb8, 0xe4, 0xc8, 0x7b, 0xff, 0x32, 0xc9, 0x69, 0x9d, 0x5b, 0x68, 0x96, 0xee, 0xe0, 0xed, 0xd1,
+			0x64, 0x02, 0x0e, 0x2b, 0xe0, 0x56, 0x08, 0x58, 0xd9, 0xc0, 0x0c, 0x03, 0x7e, 0x34, 0xa9, 0x69,
+			0x37, 0xc5, 0x61, 0xa7, 0x4c, 0x41, 0x2b, 0xb4, 0xc7, 0x46, 0x46, 0x95, 0x27, 0x28, 0x1c, 0x8c,
+		},
+	},
+	{
+		name: `KMAC256(K, 00010203, L=512, S="My Tagged Application")`,
+		run: func() ([]byte, error) {
+			key := make([]byte, 32)
+			for i := range key {
+				key[i] = 0x40 + byte(i)
+			}
+			return kmacTagKAT(key, []byte{0x00, 0x01, 0x02, 0x03}, []byte("My Tagged Application"), rate256, 512)
+		},
+		want: []byte{
+			0x20, 0xc5, 0x70, 0xc3, 0x13, 0x46, 0xf7, 0x03, 0xc9, 0xac, 0x36, 0xc6, 0x1c, 0x03, 0xcb, 0x64,
+			0xc3, 0x97, 0x0d, 0x0c, 0xfc, 0x78, 0x7e, 0x9b, 0x79, 0x59, 0x9d, 0x27, 0x3a, 0x68, 0xd2, 0xf7,
+			0xf6, 0x9d, 0x4c, 0xc3, 0xde, 0x9d, 0x10, 0x4a, 0x35, 0x16, 0x89, 0xf2, 0x7c, 0xf6, 0xf5, 0x95,
+			0x1f, 0x01, 0x03, 0xf3, 0x3f, 0x4f, 0x24, 0x87, 0x10, 0x24, 0xd9, 0xc2, 0x77, 0x73, 0xa8, 0xdd,
+		},
+	},
+}
+
+// kmacTagKAT computes the plain KMAC(K, X, L, S) tag defined in SP 800-185 section 4. It exists
+// only to validate the shared cSHAKE/bytepad/encodeString/rightEncode primitives KMACPRNG is
+// built from: KMACPRNG.Generate absorbs additional input differently (for DRBG domain
+// separation), so it can't be checked directly against the published KMAC sample.
+func kmacTagKAT(key, x, s []byte, rate, outBits int) ([]byte, error) {
+	h := newCShake(nKMAC, s, rate, dsbyteCShake)
+	if _, err := h.Write(bytepad(encodeString(key), rate)); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(x); err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(rightEncode(uint64(outBits))); err != nil {
+		return nil, err
+	}
+	out := make([]byte, outBits/8)
+	_, err := h.Read(out)
+	return out, err
+}
+
+// selfTest runs every known-answer test in selfTestKATs and reports the first mismatch.
+func selfTest() error {
+	for _, kat := range selfTestKATs {
+		got, err := kat.run()
+		if err != nil {
+			return WrapTraceableErrorf(err, "self-test %q failed to run", kat.name)
+		}
+		if !bytes.Equal(got, kat.want) {
+			return WrapTraceableErrorf(ErrSelfTestFailed, "self-test %q produced %#x, want %#x",
+				kat.name, got, kat.want)
+		}
+	}
+	return nil
+}
+
+// SelfTest runs the FIPS-202/SP 800-185 known-answer self-check exactly once per process and
+// caches the result. NewShakeDRBG calls it before allowing a DRBG to be constructed.
+func SelfTest() error {
+	selfTestOnce.Do(func() {
+		selfTestResult = selfTest()
+	})
+	return selfTestResult
+}
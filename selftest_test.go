@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSelfTestDetectsCorruption injects a fault into the KMAC computation path itself (rather
+// than corrupting an expected value) and checks that selfTest still trips against the real,
+// unmodified want values. This is what actually exercises selfTest's ability to catch a broken
+// implementation; corrupting selfTestKATs[i].want instead would only prove bytes.Equal works.
+func TestSelfTestDetectsCorruption(t *testing.T) {
+	if err := selfTest(); err != nil {
+		t.Fatalf("self-test failed on unmodified vectors: %v", err)
+	}
+	origN := nKMAC
+	nKMAC = []byte("KMAK") // cSHAKE function-name typo: changes every KMAC output
+	defer func() { nKMAC = origN }()
+	err := selfTest()
+	if err == nil {
+		t.Fatal("expected self-test to fail after corrupting nKMAC, got nil")
+	}
+	if !errors.Is(err, ErrSelfTestFailed) {
+		t.Fatalf("expected ErrSelfTestFailed, got %v", err)
+	}
+}
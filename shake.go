@@ -3,7 +3,6 @@ package main
 import (
 	"encoding/binary"
 	"io"
-  "fmt"
 )
 
 // ShakeHash defines the interface to hash functions that
@@ -111,21 +110,3 @@ func NewShake128() ShakeHash {
 func NewShake256() ShakeHash {
 	return &state{rate: rate256, dsbyte: dsbyteShake}
 }
-
-func xorIn(d *state, buf []byte) {
-  fmt.Printf("XorIn buf (%d): %#x\n", len(buf), buf)
-	n := len(buf) / 8
-
-	for i := 0; i < n; i++ {
-		a := binary.LittleEndian.Uint64(buf)
-		d.a[i] ^= a
-		buf = buf[8:]
-	}
-}
-
-func copyOut(d *state, b []byte) {
-	for i := 0; len(b) >= 8; i++ {
-		binary.LittleEndian.PutUint64(b, d.a[i])
-		b = b[8:]
-	}
-}
\ No newline at end of file
@@ -0,0 +1,23 @@
+//go:build !(amd64 || arm64 || ppc64le) || purego
+
+package main
+
+import "encoding/binary"
+
+// xorIn xors buf into d's state, 8 bytes at a time. This is the portable fallback: it works on
+// any platform and alignment, at the cost of a byte-wise Uint64 decode per word.
+func xorIn(d *state, buf []byte) {
+	n := len(buf) / 8
+	for i := 0; i < n; i++ {
+		d.a[i] ^= binary.LittleEndian.Uint64(buf)
+		buf = buf[8:]
+	}
+}
+
+// copyOut copies d's state into b, 8 bytes at a time.
+func copyOut(d *state, b []byte) {
+	for i := 0; len(b) >= 8; i++ {
+		binary.LittleEndian.PutUint64(b, d.a[i])
+		b = b[8:]
+	}
+}
@@ -0,0 +1,39 @@
+//go:build (amd64 || arm64 || ppc64le) && !purego
+
+package main
+
+import "unsafe"
+
+// maxRate is the largest Keccak rate (in bytes) used by any hash in this package (SHAKE128's
+// 168-byte rate), sized to cover every buf passed to xorIn/copyOut below.
+const maxRate = rate128
+
+// TODO(hclihn/SHAKE-PRNG#chunk0-6-followup): an in-register Keccak-f[1600] amd64 assembly
+// permutation was also requested for this chunk, along with a benchmark showing ~1.4x speedup
+// on the Generate path from it. Neither landed: keccakF1600 and the state struct it permutes
+// live outside this chunk of the tree, so the assembly can't be authored and verified safely in
+// isolation here. BenchmarkGenerate in xor_bench_test.go only records the current non-assembly
+// baseline; it does not demonstrate the requested speedup. This is a reduced-scope delivery of
+// the original request, not a completed one — needs sign-off from the request owner on whether
+// that's acceptable before this chunk is considered done, with the assembly itself tracked as a
+// separate follow-up either way.
+
+// xorIn xors buf into d's state via a single unaligned *[maxRate/8]uint64 load instead of the
+// generic path's byte-wise binary.LittleEndian.Uint64 loop. Safe only on the little-endian
+// platforms named in the build tag, which guarantee unaligned 64-bit loads work.
+func xorIn(d *state, buf []byte) {
+	n := len(buf) / 8
+	bw := (*[maxRate / 8]uint64)(unsafe.Pointer(&buf[0]))
+	for i := 0; i < n; i++ {
+		d.a[i] ^= bw[i]
+	}
+}
+
+// copyOut copies d's state into b via a single unaligned *[maxRate/8]uint64 store.
+func copyOut(d *state, b []byte) {
+	n := len(b) / 8
+	bw := (*[maxRate / 8]uint64)(unsafe.Pointer(&b[0]))
+	for i := 0; i < n; i++ {
+		bw[i] = d.a[i]
+	}
+}